@@ -0,0 +1,76 @@
+package gonotify
+
+import "context"
+
+// inotifyWatcher adapts Inotify to the Watcher interface.
+type inotifyWatcher struct {
+	inotify *Inotify
+
+	events chan FileEvent
+	errors chan error
+}
+
+// newPlatformWatcher creates the Linux Watcher backend, backed by inotify(7).
+func newPlatformWatcher(ctx context.Context) (Watcher, error) {
+
+	i, err := NewInotify(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &inotifyWatcher{
+		inotify: i,
+		events:  make(chan FileEvent),
+		errors:  make(chan error),
+	}
+
+	go w.loop()
+
+	return w, nil
+}
+
+func (w *inotifyWatcher) loop() {
+	for {
+		raw, err := w.inotify.Read()
+		if err != nil {
+			w.errors <- err
+			close(w.events)
+			close(w.errors)
+			return
+		}
+
+		for _, event := range raw {
+			w.events <- FileEvent{InotifyEvent: event}
+		}
+	}
+}
+
+func (w *inotifyWatcher) Events() <-chan FileEvent {
+	return w.events
+}
+
+func (w *inotifyWatcher) Errors() <-chan error {
+	return w.errors
+}
+
+func (w *inotifyWatcher) Add(path string, mask uint32) error {
+	_, err := w.inotify.AddWatch(path, mask)
+	return err
+}
+
+func (w *inotifyWatcher) Remove(path string) error {
+	w.inotify.mu.Lock()
+	wd, ok := w.inotify.wd[path]
+	w.inotify.mu.Unlock()
+
+	if !ok {
+		return ErrNonExistentWatch
+	}
+
+	return w.inotify.RmWd(wd)
+}
+
+func (w *inotifyWatcher) Close() error {
+	w.inotify.cancel()
+	return nil
+}
@@ -0,0 +1,282 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package gonotify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// kqueueWatcher implements Watcher on top of kqueue(2), as found on macOS and
+// the BSDs. Unlike inotify, kqueue only tells you that a watched directory's
+// content changed (NOTE_WRITE on the directory's own fd) — it doesn't say
+// which entry, or whether it was a create or a delete. So for every
+// directory watch, kqueueWatcher keeps its own listing snapshot and, on
+// NOTE_WRITE, diffs the directory against it to synthesize the per-entry
+// IN_CREATE/IN_DELETE (with IN_ISDIR where appropriate) events DirWatcher's
+// recursive-watch logic is built around.
+type kqueueWatcher struct {
+	kq int
+
+	mu      sync.Mutex
+	watches map[string]*kqueueWatch
+	fds     map[int]string
+
+	events chan FileEvent
+	errors chan error
+}
+
+// kqueueWatch tracks one watched path. entries is only populated for
+// directories: it's the last listing seen, used to diff on NOTE_WRITE.
+type kqueueWatch struct {
+	file    *os.File
+	mask    uint32
+	isDir   bool
+	entries map[string]bool // child name -> is itself a directory
+}
+
+// newPlatformWatcher creates the kqueue-backed Watcher.
+func newPlatformWatcher(ctx context.Context) (Watcher, error) {
+
+	kq, err := unix.Kqueue()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kqueue: %w", err)
+	}
+
+	w := &kqueueWatcher{
+		kq:      kq,
+		watches: make(map[string]*kqueueWatch),
+		fds:     make(map[int]string),
+		events:  make(chan FileEvent),
+		errors:  make(chan error),
+	}
+
+	go func() {
+		<-ctx.Done()
+		w.Close()
+	}()
+
+	go w.loop()
+
+	return w, nil
+}
+
+func (w *kqueueWatcher) Events() <-chan FileEvent {
+	return w.events
+}
+
+func (w *kqueueWatcher) Errors() <-chan error {
+	return w.errors
+}
+
+func (w *kqueueWatcher) Add(path string, mask uint32) error {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %w", path, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat %q: %w", path, err)
+	}
+
+	watch := &kqueueWatch{
+		file:  f,
+		mask:  mask,
+		isDir: info.IsDir(),
+	}
+
+	if watch.isDir {
+		entries, err := listDir(path)
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("failed to list %q: %w", path, err)
+		}
+		watch.entries = entries
+	}
+
+	kevent := unix.Kevent_t{
+		Ident:  uint64(f.Fd()),
+		Filter: unix.EVFILT_VNODE,
+		Flags:  unix.EV_ADD | unix.EV_CLEAR,
+		Fflags: unix.NOTE_WRITE | unix.NOTE_DELETE | unix.NOTE_RENAME | unix.NOTE_ATTRIB | unix.NOTE_EXTEND,
+	}
+
+	if _, err := unix.Kevent(w.kq, []unix.Kevent_t{kevent}, nil, nil); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to register kevent for %q: %w", path, err)
+	}
+
+	w.mu.Lock()
+	w.watches[path] = watch
+	w.fds[int(f.Fd())] = path
+	w.mu.Unlock()
+
+	return nil
+}
+
+func (w *kqueueWatcher) Remove(path string) error {
+
+	w.mu.Lock()
+	watch, ok := w.watches[path]
+	if ok {
+		delete(w.watches, path)
+		delete(w.fds, int(watch.file.Fd()))
+	}
+	w.mu.Unlock()
+
+	if !ok {
+		return ErrNonExistentWatch
+	}
+
+	return watch.file.Close()
+}
+
+func (w *kqueueWatcher) Close() error {
+	return unix.Close(w.kq)
+}
+
+func (w *kqueueWatcher) loop() {
+	events := make([]unix.Kevent_t, 16)
+
+	for {
+		n, err := unix.Kevent(w.kq, nil, events, nil)
+		if err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			w.errors <- err
+			close(w.events)
+			close(w.errors)
+			return
+		}
+
+		for _, kevent := range events[:n] {
+
+			w.mu.Lock()
+			path, ok := w.fds[int(kevent.Ident)]
+			var watch *kqueueWatch
+			if ok {
+				watch = w.watches[path]
+			}
+			w.mu.Unlock()
+
+			if !ok || watch == nil {
+				continue
+			}
+
+			if watch.isDir && kevent.Fflags&unix.NOTE_WRITE != 0 {
+				w.diffDir(path, watch)
+			}
+
+			// NOTE_WRITE on a directory's own fd just means "something in here
+			// changed" — already handled above via diffDir, so it shouldn't also
+			// be reported as a write to the directory itself.
+			mask := kqueueFflagsToSelfMask(kevent.Fflags, watch.isDir)
+			if mask == 0 || mask&watch.mask == 0 {
+				continue
+			}
+
+			if watch.isDir {
+				mask |= IN_ISDIR
+			}
+
+			w.events <- FileEvent{InotifyEvent: InotifyEvent{Name: path, Mask: mask}}
+		}
+	}
+}
+
+// diffDir re-lists path and compares it against watch's last known listing,
+// emitting a synthetic IN_CREATE or IN_DELETE (with IN_ISDIR as needed) for
+// every entry that appeared or disappeared since.
+func (w *kqueueWatcher) diffDir(path string, watch *kqueueWatch) {
+
+	entries, err := listDir(path)
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	prev := watch.entries
+	watch.entries = entries
+	mask := watch.mask
+	w.mu.Unlock()
+
+	for name, isDir := range entries {
+		if _, existed := prev[name]; existed {
+			continue
+		}
+		if mask&IN_CREATE == 0 {
+			continue
+		}
+
+		m := uint32(IN_CREATE)
+		if isDir {
+			m |= IN_ISDIR
+		}
+		w.events <- FileEvent{InotifyEvent: InotifyEvent{Name: filepath.Join(path, name), Mask: m}}
+	}
+
+	for name, isDir := range prev {
+		if _, still := entries[name]; still {
+			continue
+		}
+		if mask&IN_DELETE == 0 {
+			continue
+		}
+
+		m := uint32(IN_DELETE)
+		if isDir {
+			m |= IN_ISDIR
+		}
+		w.events <- FileEvent{InotifyEvent: InotifyEvent{Name: filepath.Join(path, name), Mask: m}}
+	}
+}
+
+// listDir returns path's immediate children, keyed by name, recording
+// whether each one is itself a directory.
+func listDir(path string) (map[string]bool, error) {
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	listing := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		listing[e.Name()] = e.IsDir()
+	}
+
+	return listing, nil
+}
+
+// kqueueFflagsToSelfMask translates fflags describing a change to the
+// watched path itself (as opposed to one of a directory's children, which
+// diffDir handles) into the matching IN_* bits. A watched directory that is
+// itself removed or renamed away surfaces as IN_DELETE_SELF/IN_MOVE_SELF,
+// mirroring inotify.
+func kqueueFflagsToSelfMask(fflags uint32, isDir bool) uint32 {
+	var mask uint32
+
+	if fflags&unix.NOTE_DELETE != 0 {
+		mask |= IN_DELETE_SELF
+	}
+	if fflags&unix.NOTE_RENAME != 0 {
+		mask |= IN_MOVE_SELF
+	}
+	if fflags&unix.NOTE_ATTRIB != 0 {
+		mask |= IN_ATTRIB
+	}
+	if !isDir && fflags&(unix.NOTE_WRITE|unix.NOTE_EXTEND) != 0 {
+		mask |= IN_MODIFY
+	}
+
+	return mask
+}
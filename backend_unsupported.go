@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows
+
+package gonotify
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+// newPlatformWatcher reports that this platform (e.g. illumos, which would
+// need cgo bindings to port_create(3C)/port_get(3C) that golang.org/x/sys
+// doesn't provide) has no native Watcher backend. Callers that want to run
+// here anyway should use NewAutoDirWatcher, which falls back to
+// NewPollingDirWatcher when this error is returned.
+func newPlatformWatcher(ctx context.Context) (Watcher, error) {
+	return nil, fmt.Errorf("gonotify: no native Watcher backend for %s/%s", runtime.GOOS, runtime.GOARCH)
+}
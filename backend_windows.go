@@ -0,0 +1,189 @@
+package gonotify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// windowsWatcher implements Watcher on top of ReadDirectoryChangesW, the
+// native Windows directory change notification API.
+type windowsWatcher struct {
+	mu      sync.Mutex
+	watches map[string]*windowsWatch
+
+	events chan FileEvent
+	errors chan error
+}
+
+type windowsWatch struct {
+	handle windows.Handle
+	path   string
+	mask   uint32
+	buf    [64 * 1024]byte
+}
+
+// newPlatformWatcher creates the Windows Watcher backend.
+func newPlatformWatcher(ctx context.Context) (Watcher, error) {
+
+	w := &windowsWatcher{
+		watches: make(map[string]*windowsWatch),
+		events:  make(chan FileEvent),
+		errors:  make(chan error),
+	}
+
+	go func() {
+		<-ctx.Done()
+		w.Close()
+	}()
+
+	return w, nil
+}
+
+func (w *windowsWatcher) Events() <-chan FileEvent {
+	return w.events
+}
+
+func (w *windowsWatcher) Errors() <-chan error {
+	return w.errors
+}
+
+func (w *windowsWatcher) Add(path string, mask uint32) error {
+
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return err
+	}
+
+	handle, err := windows.CreateFile(
+		p,
+		windows.FILE_LIST_DIRECTORY,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil,
+		windows.OPEN_EXISTING,
+		windows.FILE_FLAG_BACKUP_SEMANTICS,
+		0,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to open %q: %w", path, err)
+	}
+
+	watch := &windowsWatch{
+		handle: handle,
+		path:   path,
+		mask:   mask,
+	}
+
+	w.mu.Lock()
+	w.watches[path] = watch
+	w.mu.Unlock()
+
+	go w.readChanges(watch)
+
+	return nil
+}
+
+func (w *windowsWatcher) Remove(path string) error {
+
+	w.mu.Lock()
+	watch, ok := w.watches[path]
+	if ok {
+		delete(w.watches, path)
+	}
+	w.mu.Unlock()
+
+	if !ok {
+		return ErrNonExistentWatch
+	}
+
+	return windows.CloseHandle(watch.handle)
+}
+
+func (w *windowsWatcher) Close() error {
+
+	w.mu.Lock()
+	watches := w.watches
+	w.watches = make(map[string]*windowsWatch)
+	w.mu.Unlock()
+
+	for _, watch := range watches {
+		windows.CloseHandle(watch.handle)
+	}
+
+	return nil
+}
+
+const windowsNotifyFilter = windows.FILE_NOTIFY_CHANGE_FILE_NAME |
+	windows.FILE_NOTIFY_CHANGE_DIR_NAME |
+	windows.FILE_NOTIFY_CHANGE_ATTRIBUTES |
+	windows.FILE_NOTIFY_CHANGE_SIZE |
+	windows.FILE_NOTIFY_CHANGE_LAST_WRITE
+
+func (w *windowsWatcher) readChanges(watch *windowsWatch) {
+	for {
+		var bytesReturned uint32
+
+		// watchSubtree is false: DirWatcher already adds its own watch for
+		// every subdirectory it discovers (as the inotify and kqueue backends
+		// expect), so watching recursively here too would report every event
+		// once per ancestor directory.
+		err := windows.ReadDirectoryChanges(
+			watch.handle,
+			&watch.buf[0],
+			uint32(len(watch.buf)),
+			false,
+			windowsNotifyFilter,
+			&bytesReturned,
+			nil,
+			0,
+		)
+		if err != nil {
+			if err == windows.ERROR_OPERATION_ABORTED {
+				return
+			}
+			w.errors <- err
+			return
+		}
+
+		offset := uint32(0)
+		for {
+			raw := (*windows.FileNotifyInformation)(unsafe.Pointer(&watch.buf[offset]))
+
+			name := syscall.UTF16ToString((*[syscall.MAX_PATH]uint16)(unsafe.Pointer(&raw.FileName))[:raw.FileNameLength/2])
+
+			op := windowsActionToOp(raw.Action)
+			mask := opToMask(op)
+
+			if mask&watch.mask != 0 {
+				w.events <- FileEvent{
+					InotifyEvent: InotifyEvent{
+						Name: watch.path + string('\\') + name,
+						Mask: mask,
+					},
+				}
+			}
+
+			if raw.NextEntryOffset == 0 {
+				break
+			}
+			offset += raw.NextEntryOffset
+		}
+	}
+}
+
+func windowsActionToOp(action uint32) Op {
+	switch action {
+	case windows.FILE_ACTION_ADDED, windows.FILE_ACTION_RENAMED_NEW_NAME:
+		return Create
+	case windows.FILE_ACTION_REMOVED, windows.FILE_ACTION_RENAMED_OLD_NAME:
+		return Remove
+	case windows.FILE_ACTION_MODIFIED:
+		return Write
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,205 @@
+package gonotify
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewCoalescingDirWatcher wraps NewDirWatcher with a layer that smooths out
+// the bursty, occasionally duplicated or out-of-order event stream inotify
+// produces for what is really a single user action: repeated IN_MODIFY and
+// IN_ATTRIB events for the same path arriving within window of each other are
+// collapsed into one, and IN_MOVED_FROM/IN_MOVED_TO pairs sharing the same
+// Cookie are joined into a single FileEvent carrying both names, via the new
+// RenamedFrom field. A MOVED_FROM (or MOVED_TO) whose partner doesn't arrive
+// within window is flushed on its own as a plain move-away (or move-in) event.
+func NewCoalescingDirWatcher(ctx context.Context, fileMask uint32, root string, window time.Duration) (*DirWatcher, error) {
+
+	// IN_MOVED_FROM/IN_MOVED_TO need to reach the coalescer even if the caller
+	// didn't ask for them, so cookie correlation keeps working; they are
+	// filtered back out against fileMask before being handed to the caller.
+	inner, err := NewDirWatcher(ctx, fileMask|IN_MOVED_FROM|IN_MOVED_TO, root)
+	if err != nil {
+		return nil, err
+	}
+
+	// dw shares inner's mu (not just its trees/roots maps): both DirWatchers'
+	// AddTree/RemoveTree, and inner's own recursive watch-on-create goroutine,
+	// mutate those maps, so they must serialize on the same lock.
+	dw := &DirWatcher{
+		C:       make(chan FileEvent),
+		watcher: inner.watcher,
+		mu:      inner.mu,
+		trees:   inner.trees,
+		roots:   inner.roots,
+		root:    inner.root,
+		include: inner.include,
+		exclude: inner.exclude,
+	}
+
+	c := &coalescer{
+		out:      dw.C,
+		fileMask: fileMask,
+		window:   window,
+		modified: make(map[string]*coalescedEvent),
+		moves:    make(map[uint32]*coalescedEvent),
+		done:     make(chan struct{}),
+	}
+
+	go c.run(inner.C)
+
+	return dw, nil
+}
+
+// coalescedEvent is a buffered event waiting either for more of the same
+// (IN_MODIFY/IN_ATTRIB bursts) or for a correlating partner (IN_MOVED_TO), to
+// be flushed once window has elapsed without one showing up.
+type coalescedEvent struct {
+	event FileEvent
+	timer *time.Timer
+}
+
+type coalescer struct {
+	out      chan<- FileEvent
+	fileMask uint32
+	window   time.Duration
+
+	mu       sync.Mutex
+	modified map[string]*coalescedEvent
+	moves    map[uint32]*coalescedEvent
+
+	done chan struct{}
+}
+
+func (c *coalescer) run(in <-chan FileEvent) {
+	for event := range in {
+		if event.Eof {
+			c.flushAll()
+			close(c.done)
+			c.out <- event
+			return
+		}
+
+		switch {
+		case event.Mask&(IN_MODIFY|IN_ATTRIB) != 0:
+			c.coalesceModify(event)
+
+		case event.Mask&IN_MOVED_FROM == IN_MOVED_FROM:
+			c.startMove(event)
+
+		case event.Mask&IN_MOVED_TO == IN_MOVED_TO:
+			c.joinMove(event)
+
+		default:
+			c.emit(event)
+		}
+	}
+}
+
+// emit delivers event to the caller, applying the mask the caller actually
+// asked for (fileMask may be narrower than what NewDirWatcher was told to
+// watch for, since MOVED_FROM/MOVED_TO are always requested internally).
+func (c *coalescer) emit(event FileEvent) {
+	if event.Mask&c.fileMask == 0 {
+		return
+	}
+
+	select {
+	case c.out <- event:
+	case <-c.done:
+	}
+}
+
+func (c *coalescer) coalesceModify(event FileEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending, ok := c.modified[event.Name]
+	if ok {
+		pending.timer.Stop()
+		pending.event.Mask |= event.Mask
+	} else {
+		pending = &coalescedEvent{event: event}
+		c.modified[event.Name] = pending
+	}
+
+	name := event.Name
+	pending.timer = time.AfterFunc(c.window, func() { c.flushModify(name) })
+}
+
+func (c *coalescer) flushModify(name string) {
+	c.mu.Lock()
+	pending, ok := c.modified[name]
+	if ok {
+		delete(c.modified, name)
+	}
+	c.mu.Unlock()
+
+	if ok {
+		c.emit(pending.event)
+	}
+}
+
+func (c *coalescer) startMove(event FileEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cookie := event.Cookie
+	pending := &coalescedEvent{event: event}
+	c.moves[cookie] = pending
+	pending.timer = time.AfterFunc(c.window, func() { c.flushMove(cookie) })
+}
+
+func (c *coalescer) flushMove(cookie uint32) {
+	c.mu.Lock()
+	pending, ok := c.moves[cookie]
+	if ok {
+		delete(c.moves, cookie)
+	}
+	c.mu.Unlock()
+
+	if ok {
+		c.emit(pending.event)
+	}
+}
+
+func (c *coalescer) joinMove(event FileEvent) {
+	c.mu.Lock()
+	from, ok := c.moves[event.Cookie]
+	if ok {
+		from.timer.Stop()
+		delete(c.moves, event.Cookie)
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		// No MOVED_FROM with this cookie was seen in time (or at all): the
+		// file was moved in from outside the watched tree, so deliver the
+		// move-in event on its own.
+		c.emit(event)
+		return
+	}
+
+	joined := event
+	joined.RenamedFrom = from.event.Name
+	c.emit(joined)
+}
+
+func (c *coalescer) flushAll() {
+	c.mu.Lock()
+	modified := c.modified
+	moves := c.moves
+	c.modified = nil
+	c.moves = nil
+	c.mu.Unlock()
+
+	for _, pending := range modified {
+		pending.timer.Stop()
+		c.emit(pending.event)
+	}
+	for _, pending := range moves {
+		pending.timer.Stop()
+		c.emit(pending.event)
+	}
+}
@@ -0,0 +1,51 @@
+package gonotify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestCoalescingDirWatcherJoinsRenameWithFullPaths drives a real rename
+// through NewCoalescingDirWatcher end-to-end (inotify, DirWatcher and the
+// coalescer all wired together, not just the coalescer in isolation), to
+// catch regressions like the bare-filename Name/RenamedFrom bug that the
+// coalescer's own unit tests, feeding it synthetic FileEvents directly,
+// can't see.
+func TestCoalescingDirWatcherJoinsRenameWithFullPaths(t *testing.T) {
+	root := t.TempDir()
+	oldPath := filepath.Join(root, "old.txt")
+	newPath := filepath.Join(root, "new.txt")
+
+	if err := os.WriteFile(oldPath, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dw, err := NewCoalescingDirWatcher(ctx, IN_ALL_EVENTS, root, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewCoalescingDirWatcher: %v", err)
+	}
+
+	// Pre-existing file: the synthetic startup IN_CREATE.
+	initial := recvWithin(t, dw.C, time.Second)
+	if initial.Name != oldPath {
+		t.Fatalf("initial event Name = %q, want full path %q", initial.Name, oldPath)
+	}
+
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := recvWithin(t, dw.C, time.Second)
+	if renamed.Name != newPath {
+		t.Errorf("renamed event Name = %q, want full path %q", renamed.Name, newPath)
+	}
+	if renamed.RenamedFrom != oldPath {
+		t.Errorf("renamed event RenamedFrom = %q, want full path %q", renamed.RenamedFrom, oldPath)
+	}
+}
@@ -0,0 +1,101 @@
+package gonotify
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestCoalescer(window time.Duration) (*coalescer, chan FileEvent) {
+	out := make(chan FileEvent, 16)
+	c := &coalescer{
+		out:      out,
+		fileMask: IN_ALL_EVENTS,
+		window:   window,
+		modified: make(map[string]*coalescedEvent),
+		moves:    make(map[uint32]*coalescedEvent),
+		done:     make(chan struct{}),
+	}
+	return c, out
+}
+
+func recvWithin(t *testing.T, out chan FileEvent, d time.Duration) FileEvent {
+	t.Helper()
+	select {
+	case e := <-out:
+		return e
+	case <-time.After(d):
+		t.Fatal("timed out waiting for event")
+		return FileEvent{}
+	}
+}
+
+func assertNoEventWithin(t *testing.T, out chan FileEvent, d time.Duration) {
+	t.Helper()
+	select {
+	case e := <-out:
+		t.Fatalf("expected no event, got %+v", e)
+	case <-time.After(d):
+	}
+}
+
+func TestCoalescerCoalescesRepeatedModify(t *testing.T) {
+	c, out := newTestCoalescer(20 * time.Millisecond)
+
+	c.coalesceModify(FileEvent{InotifyEvent: InotifyEvent{Name: "a.txt", Mask: IN_MODIFY}})
+	c.coalesceModify(FileEvent{InotifyEvent: InotifyEvent{Name: "a.txt", Mask: IN_ATTRIB}})
+
+	assertNoEventWithin(t, out, 10*time.Millisecond)
+
+	event := recvWithin(t, out, 50*time.Millisecond)
+	if event.Name != "a.txt" {
+		t.Errorf("Name = %q, want %q", event.Name, "a.txt")
+	}
+	if event.Mask != IN_MODIFY|IN_ATTRIB {
+		t.Errorf("Mask = %#x, want %#x", event.Mask, IN_MODIFY|IN_ATTRIB)
+	}
+}
+
+func TestCoalescerJoinsMoveWithMatchingCookie(t *testing.T) {
+	c, out := newTestCoalescer(20 * time.Millisecond)
+
+	c.startMove(FileEvent{InotifyEvent: InotifyEvent{Name: "old", Mask: IN_MOVED_FROM, Cookie: 42}})
+	c.joinMove(FileEvent{InotifyEvent: InotifyEvent{Name: "new", Mask: IN_MOVED_TO, Cookie: 42}})
+
+	event := recvWithin(t, out, 50*time.Millisecond)
+	if event.Name != "new" || event.RenamedFrom != "old" {
+		t.Errorf("got Name=%q RenamedFrom=%q, want Name=%q RenamedFrom=%q", event.Name, event.RenamedFrom, "new", "old")
+	}
+}
+
+func TestCoalescerFlushesUnmatchedMoveAfterWindow(t *testing.T) {
+	c, out := newTestCoalescer(10 * time.Millisecond)
+
+	c.startMove(FileEvent{InotifyEvent: InotifyEvent{Name: "gone", Mask: IN_MOVED_FROM, Cookie: 7}})
+
+	assertNoEventWithin(t, out, 5*time.Millisecond)
+
+	event := recvWithin(t, out, 50*time.Millisecond)
+	if event.Name != "gone" || event.Mask != IN_MOVED_FROM {
+		t.Errorf("got %+v, want a lone MOVED_FROM for %q", event, "gone")
+	}
+}
+
+func TestCoalescerEmitsMoveInWithoutPartnerOnItsOwn(t *testing.T) {
+	c, out := newTestCoalescer(20 * time.Millisecond)
+
+	c.joinMove(FileEvent{InotifyEvent: InotifyEvent{Name: "arrived", Mask: IN_MOVED_TO, Cookie: 99}})
+
+	event := recvWithin(t, out, 50*time.Millisecond)
+	if event.Name != "arrived" || event.RenamedFrom != "" {
+		t.Errorf("got %+v, want an unjoined MOVED_TO for %q", event, "arrived")
+	}
+}
+
+func TestCoalescerEmitRespectsFileMask(t *testing.T) {
+	c, out := newTestCoalescer(20 * time.Millisecond)
+	c.fileMask = IN_CREATE // caller never asked for IN_ATTRIB
+
+	c.emit(FileEvent{InotifyEvent: InotifyEvent{Name: "a", Mask: IN_ATTRIB}})
+
+	assertNoEventWithin(t, out, 10*time.Millisecond)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 )
 
 // DirWatcher recursively watches the given root folder, waiting for file events.
@@ -11,21 +13,148 @@ import (
 // folders or subfolders.
 type DirWatcher struct {
 	C chan FileEvent
+
+	watcher Watcher
+
+	mu    *sync.Mutex
+	trees map[string]map[string]bool // tree root -> set of paths watched under it (including the root itself)
+	roots map[string]string          // watched path -> the tree root it belongs to
+
+	root             string
+	include, exclude *patternSet
+}
+
+// rel returns path relative to dw.root, for matching against Include/Exclude
+// patterns, falling back to path itself if it isn't under root.
+func (dw *DirWatcher) rel(path string) string {
+	rel, err := filepath.Rel(dw.root, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// excluded reports whether path matches dw's Exclude patterns. It is used to
+// decide whether to prune a directory from the watched tree entirely: unlike
+// filtered, it ignores Include, since a directory not matching Include may
+// still contain files that do.
+func (dw *DirWatcher) excluded(path string) bool {
+	return dw.exclude.match(dw.rel(path))
+}
+
+// filtered reports whether path should be skipped, according to dw's
+// Include/Exclude options. A DirWatcher created without options (or with a
+// zero Options) never filters anything.
+func (dw *DirWatcher) filtered(path string) bool {
+	if dw.include.empty() && dw.exclude.empty() {
+		return false
+	}
+
+	rel := dw.rel(path)
+
+	if dw.exclude.match(rel) {
+		return true
+	}
+
+	return !dw.include.empty() && !dw.include.match(rel)
+}
+
+// track records path as belonging to the tree rooted at root, so that a
+// later RemoveTree(root) knows to tear it down too.
+func (dw *DirWatcher) track(root, path string) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+
+	if dw.trees[root] == nil {
+		dw.trees[root] = make(map[string]bool)
+	}
+	dw.trees[root][path] = true
+	dw.roots[path] = root
+}
+
+// trackChild records path as belonging to whichever tree its parent folder
+// already belongs to. It is used when the recursive watcher discovers a new
+// subfolder created at runtime.
+func (dw *DirWatcher) trackChild(path string) {
+	dw.mu.Lock()
+	root, ok := dw.roots[filepath.Dir(path)]
+	dw.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	dw.track(root, path)
+}
+
+// removeSubtree stops watching path and every path recorded under the same
+// tree that is a descendant of it (or path itself), used when a watched
+// folder is deleted or moved away.
+func (dw *DirWatcher) removeSubtree(path string) {
+	dw.mu.Lock()
+	root, ok := dw.roots[path]
+	var descendants []string
+	if ok {
+		prefix := path + string(os.PathSeparator)
+		for p := range dw.trees[root] {
+			if p == path || strings.HasPrefix(p, prefix) {
+				descendants = append(descendants, p)
+			}
+		}
+		for _, p := range descendants {
+			delete(dw.trees[root], p)
+			delete(dw.roots, p)
+		}
+		if len(dw.trees[root]) == 0 {
+			delete(dw.trees, root)
+		}
+	}
+	dw.mu.Unlock()
+
+	for _, p := range descendants {
+		dw.watcher.Remove(p)
+	}
 }
 
 // NewDirWatcher creates DirWatcher recursively waiting for events in the given root folder and
 // emitting FileEvents in channel C, that correspond to fileMask. Folder events are ignored (having IN_ISDIR set to 1)
+//
+// NewDirWatcher picks whichever Watcher backend the current platform provides (inotify on Linux,
+// kqueue on *BSD/macOS, ReadDirectoryChangesW on Windows), so it works the same way everywhere a
+// native backend exists; see NewAutoDirWatcher for platforms (like illumos) that don't have one.
 func NewDirWatcher(ctx context.Context, fileMask uint32, root string) (*DirWatcher, error) {
+	return NewDirWatcherWithOptions(ctx, fileMask, root, Options{})
+}
+
+// NewDirWatcherWithOptions is like NewDirWatcher, but additionally filters
+// the watched tree and the delivered events through opts: Exclude patterns
+// keep whole subtrees (e.g. "node_modules" or ".git") from ever being
+// watched, and Include/Exclude together decide which events reach C.
+func NewDirWatcherWithOptions(ctx context.Context, fileMask uint32, root string, opts Options) (*DirWatcher, error) {
+
+	include, exclude, err := opts.patternSets(root)
+	if err != nil {
+		return nil, err
+	}
+
 	dw := &DirWatcher{
-		C: make(chan FileEvent),
+		C:       make(chan FileEvent),
+		mu:      &sync.Mutex{},
+		trees:   make(map[string]map[string]bool),
+		roots:   make(map[string]string),
+		root:    root,
+		include: include,
+		exclude: exclude,
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
 
-	i, err := NewInotify(ctx)
+	w, err := newWatcher(ctx)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
+	dw.watcher = w
 
 	queue := make([]FileEvent, 0, 100)
 
@@ -35,20 +164,32 @@ func NewDirWatcher(ctx context.Context, fileMask uint32, root string) (*DirWatch
 			return nil
 		}
 
-		if !f.IsDir() {
+		if f.IsDir() {
+			if dw.excluded(path) {
+				return filepath.SkipDir
+			}
 
-			//fake event for existing files
-			queue = append(queue, FileEvent{
-				InotifyEvent: InotifyEvent{
-					Name: path,
-					Mask: IN_CREATE,
-				},
-			})
+			if err := w.Add(path, IN_ALL_EVENTS); err != nil {
+				return err
+			}
+			dw.track(root, path)
+
+			return nil
+		}
 
+		if dw.filtered(path) {
 			return nil
 		}
-		_, err = i.AddWatch(path, IN_ALL_EVENTS)
-		return err
+
+		//fake event for existing files
+		queue = append(queue, FileEvent{
+			InotifyEvent: InotifyEvent{
+				Name: path,
+				Mask: IN_CREATE,
+			},
+		})
+
+		return nil
 	})
 
 	if err != nil {
@@ -66,13 +207,16 @@ func NewDirWatcher(ctx context.Context, fileMask uint32, root string) (*DirWatch
 
 		for {
 
-			raw, err := i.Read()
-			if err != nil {
+			select {
+			case <-w.Errors():
 				close(events)
 				return
-			}
 
-			for _, event := range raw {
+			case event, ok := <-w.Events():
+				if !ok {
+					close(events)
+					return
+				}
 
 				// Skip ignored events queued from removed watchers
 				if event.Mask&IN_IGNORED == IN_IGNORED {
@@ -82,6 +226,10 @@ func NewDirWatcher(ctx context.Context, fileMask uint32, root string) (*DirWatch
 				// Add watch for folders created in watched folders (recursion)
 				if event.Mask&(IN_CREATE|IN_ISDIR) == IN_CREATE|IN_ISDIR {
 
+					if dw.excluded(event.Name) {
+						continue
+					}
+
 					// After the watch for subfolder is added, it may be already late to detect files
 					// created there right after subfolder creation, so we should generate such events
 					// ourselves:
@@ -104,14 +252,33 @@ func NewDirWatcher(ctx context.Context, fileMask uint32, root string) (*DirWatch
 					})
 
 					// Wait for further files to be added
-					i.AddWatch(event.Name, IN_ALL_EVENTS)
+					w.Add(event.Name, IN_ALL_EVENTS)
+					dw.trackChild(event.Name)
 
 					continue
 				}
 
-				// Remove watch for deleted folders
+				// Remove watch (and any descendant watches) for a deleted folder. This
+				// fires on the deleted folder's own watch, with Name resolved from its
+				// Wd; removeSubtree is idempotent, so it's fine if the parent-reported
+				// IN_DELETE|IN_ISDIR below also observes the same removal.
 				if event.Mask&IN_DELETE_SELF == IN_DELETE_SELF {
-					i.RmWd(event.Wd)
+					dw.removeSubtree(event.Name)
+					continue
+				}
+
+				// A folder was deleted, reported by its parent's watch with Name set to
+				// the child's path.
+				if event.Mask&(IN_DELETE|IN_ISDIR) == IN_DELETE|IN_ISDIR {
+					dw.removeSubtree(event.Name)
+					continue
+				}
+
+				// A watched folder moved away from under its parent: its own watch is still
+				// valid, but it (and anything below it) is no longer reachable from root, so
+				// tear down the whole subtree to avoid leaking watches.
+				if event.Mask&(IN_MOVED_FROM|IN_ISDIR) == IN_MOVED_FROM|IN_ISDIR {
+					dw.removeSubtree(event.Name)
 					continue
 				}
 
@@ -120,9 +287,7 @@ func NewDirWatcher(ctx context.Context, fileMask uint32, root string) (*DirWatch
 					continue
 				}
 
-				events <- FileEvent{
-					InotifyEvent: event,
-				}
+				events <- event
 			}
 		}
 	}()
@@ -146,6 +311,11 @@ func NewDirWatcher(ctx context.Context, fileMask uint32, root string) (*DirWatch
 					continue
 				}
 
+				// Skip events for paths excluded (or not included) by Options
+				if dw.filtered(event.Name) {
+					continue
+				}
+
 				dw.C <- event
 			}
 		}
@@ -154,3 +324,61 @@ func NewDirWatcher(ctx context.Context, fileMask uint32, root string) (*DirWatch
 	return dw, nil
 
 }
+
+// AddTree starts watching path and all of its subfolders, growing the set of
+// folders this DirWatcher watches at runtime. New events observed under path
+// are delivered on the same C channel as everything else. It behaves like the
+// recursive walk NewDirWatcher performs at construction time, but can be
+// called after the DirWatcher is already running. Unlike that walk, it emits
+// no synthetic IN_CREATE events for files already under path, and it returns
+// the first error the walk hits.
+func (dw *DirWatcher) AddTree(path string) error {
+	return filepath.Walk(path, func(p string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !f.IsDir() {
+			return nil
+		}
+
+		if dw.excluded(p) {
+			return filepath.SkipDir
+		}
+
+		if err := dw.watcher.Add(p, IN_ALL_EVENTS); err != nil {
+			return err
+		}
+		dw.track(path, p)
+
+		return nil
+	})
+}
+
+// RemoveTree stops watching path and every descendant watch established
+// under it, whether by NewDirWatcher's initial walk, a prior AddTree, or the
+// recursive watch-on-create logic picking up folders created at runtime. It
+// returns ErrNonExistentWatch if path is not the root of a currently watched
+// tree: it was never passed to NewDirWatcher or AddTree, or its tree has
+// already been removed.
+//
+// On a DirWatcher created by NewPollingDirWatcher, RemoveTree only drops the
+// recorded watches: polling still scans the whole root, so events under path
+// keep being delivered.
+func (dw *DirWatcher) RemoveTree(path string) error {
+	dw.mu.Lock()
+	_, ok := dw.trees[path]
+	dw.mu.Unlock()
+
+	if !ok {
+		return ErrNonExistentWatch
+	}
+
+	dw.removeSubtree(path)
+
+	dw.mu.Lock()
+	delete(dw.trees, path)
+	dw.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,12 @@
+// Package gonotify provides a cross-platform recursive directory watcher,
+// built on whichever native filesystem event API the current platform
+// offers: inotify on Linux, kqueue on *BSD/macOS, and ReadDirectoryChangesW
+// on Windows. See DirWatcher, NewAutoDirWatcher and NewPollingDirWatcher.
+//
+// Scope note: illumos was originally meant to get its own native backend via
+// FEN (port_create(3C)/port_get(3C)), but golang.org/x/sys/unix has no
+// pure-Go binding for those syscalls — fsnotify itself only reaches them
+// through cgo — so that backend was dropped rather than shipped non-building.
+// illumos instead falls back to NewAutoDirWatcher's polling watcher; see
+// backend_unsupported.go.
+package gonotify
@@ -0,0 +1,7 @@
+package gonotify
+
+import "errors"
+
+// ErrNonExistentWatch is returned when trying to remove a watch, or a tree of
+// watches, for a path that is not currently being watched.
+var ErrNonExistentWatch = errors.New("gonotify: no watch exists for the given path")
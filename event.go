@@ -0,0 +1,35 @@
+package gonotify
+
+// InotifyEvent describes a single filesystem event, normalized to the
+// inotify(7) vocabulary (Mask is a bitwise OR of IN_* constants) regardless
+// of which backend produced it.
+type InotifyEvent struct {
+	// Watch descriptor
+	Wd uint32
+
+	// File or directory name
+	Name string
+
+	// Mask of events
+	Mask uint32
+
+	// Cookie, that can be used to connect related events (for example, IN_MOVED_FROM and IN_MOVED_TO)
+	Cookie uint32
+}
+
+// FileEvent wraps InotifyEvent, providing Eof indicator, that is passed
+// via channel, when watching is done
+type FileEvent struct {
+	InotifyEvent
+
+	// Eof is true when the underlying watcher has stopped on its own (for
+	// example after an unrecoverable read error) and no further events will
+	// arrive. Cancelling the context given to the constructor stops delivery
+	// on C without a guaranteed Eof event.
+	Eof bool
+
+	// RenamedFrom holds the old name of a file that was renamed, when this
+	// event is the result of NewCoalescingDirWatcher joining an IN_MOVED_FROM
+	// and IN_MOVED_TO pair sharing the same Cookie. It is empty otherwise.
+	RenamedFrom string
+}
@@ -0,0 +1,151 @@
+package gonotify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Inotify is the low-level wrapper around the inotify syscalls, as described in inotify(7).
+type Inotify struct {
+	fd     int
+	file   *os.File
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	wd    map[string]uint32
+	paths map[uint32]string // reverse of wd, used to turn a raw event's Wd into a full path
+}
+
+// NewInotify creates new inotify instance. It can fail, if inotify instance
+// can't be created (see inotify(7) for possible reasons), most commons are
+// the limit on the number of inotify instances or a lack of privileges.
+func NewInotify(ctx context.Context) (*Inotify, error) {
+
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize inotify: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	i := &Inotify{
+		fd:     fd,
+		file:   os.NewFile(uintptr(fd), ""),
+		ctx:    ctx,
+		cancel: cancel,
+		wd:     make(map[string]uint32),
+		paths:  make(map[uint32]string),
+	}
+
+	go func() {
+		<-ctx.Done()
+		i.file.Close()
+	}()
+
+	return i, nil
+}
+
+// AddWatch adds given path to the list of watched files/folders, with the given mask.
+// Mask is the bitmask of IN_* constants, combined with bitwise OR.
+func (i *Inotify) AddWatch(pathName string, mask uint32) (uint32, error) {
+
+	wd, err := unix.InotifyAddWatch(i.fd, pathName, mask)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add watch for %q: %w", pathName, err)
+	}
+
+	i.mu.Lock()
+	i.wd[pathName] = uint32(wd)
+	i.paths[uint32(wd)] = pathName
+	i.mu.Unlock()
+
+	return uint32(wd), nil
+}
+
+// RmWd removes given watch descriptor from the list of watched files/folders.
+func (i *Inotify) RmWd(wd uint32) error {
+
+	_, err := unix.InotifyRmWatch(i.fd, wd)
+	if err != nil {
+		return fmt.Errorf("failed to remove watch: %w", err)
+	}
+
+	i.mu.Lock()
+	for path, w := range i.wd {
+		if w == wd {
+			delete(i.wd, path)
+		}
+	}
+	delete(i.paths, wd)
+	i.mu.Unlock()
+
+	return nil
+}
+
+// Read reads portion of InotifyEvents and may block if there are no events.
+// Read is not thread safe, and should be called only from single thread.
+func (i *Inotify) Read() ([]InotifyEvent, error) {
+
+	buf := make([]byte, unix.SizeofInotifyEvent*4096)
+
+	n, err := i.file.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if n < unix.SizeofInotifyEvent {
+		return nil, fmt.Errorf("short read while reading inotify event")
+	}
+
+	var result []InotifyEvent
+
+	offset := 0
+	for offset+unix.SizeofInotifyEvent <= n {
+
+		raw := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+
+		i.mu.Lock()
+		watchPath := i.paths[uint32(raw.Wd)]
+		i.mu.Unlock()
+
+		event := InotifyEvent{
+			Wd:     uint32(raw.Wd),
+			Mask:   raw.Mask,
+			Cookie: raw.Cookie,
+			Name:   watchPath,
+		}
+
+		nameLen := int(raw.Len)
+		offset += unix.SizeofInotifyEvent
+
+		if nameLen > 0 {
+			rawName := stringFromNullTerminated(buf[offset : offset+nameLen])
+			if watchPath != "" {
+				event.Name = filepath.Join(watchPath, rawName)
+			} else {
+				event.Name = rawName
+			}
+		}
+		offset += nameLen
+
+		result = append(result, event)
+	}
+
+	return result, nil
+}
+
+func stringFromNullTerminated(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,62 @@
+package gonotify
+
+// Op describes a set of file operations, portable across the platform-specific
+// backends that implement Watcher. It lets callers reason about what happened
+// to a path without caring whether the underlying backend is inotify, kqueue,
+// or ReadDirectoryChangesW.
+type Op uint32
+
+const (
+	Create Op = 1 << iota
+	Write
+	Remove
+	Rename
+	Chmod
+)
+
+// opToMask translates a portable Op into the equivalent IN_* bitmask, so that
+// FileEvent.Mask stays meaningful regardless of which backend produced the event.
+func opToMask(op Op) uint32 {
+	var mask uint32
+
+	if op&Create == Create {
+		mask |= IN_CREATE
+	}
+	if op&Write == Write {
+		mask |= IN_MODIFY
+	}
+	if op&Remove == Remove {
+		mask |= IN_DELETE
+	}
+	if op&Rename == Rename {
+		mask |= IN_MOVED_FROM | IN_MOVED_TO
+	}
+	if op&Chmod == Chmod {
+		mask |= IN_ATTRIB
+	}
+
+	return mask
+}
+
+// maskToOp translates an IN_* bitmask into the portable Op it corresponds to.
+func maskToOp(mask uint32) Op {
+	var op Op
+
+	if mask&IN_CREATE == IN_CREATE {
+		op |= Create
+	}
+	if mask&IN_MODIFY == IN_MODIFY {
+		op |= Write
+	}
+	if mask&IN_DELETE == IN_DELETE || mask&IN_DELETE_SELF == IN_DELETE_SELF {
+		op |= Remove
+	}
+	if mask&(IN_MOVED_FROM|IN_MOVED_TO|IN_MOVE_SELF) != 0 {
+		op |= Rename
+	}
+	if mask&IN_ATTRIB == IN_ATTRIB {
+		op |= Chmod
+	}
+
+	return op
+}
@@ -0,0 +1,62 @@
+package gonotify
+
+import "testing"
+
+func TestOpToMask(t *testing.T) {
+	cases := []struct {
+		name string
+		op   Op
+		want uint32
+	}{
+		{"create", Create, IN_CREATE},
+		{"write", Write, IN_MODIFY},
+		{"remove", Remove, IN_DELETE},
+		{"rename", Rename, IN_MOVED_FROM | IN_MOVED_TO},
+		{"chmod", Chmod, IN_ATTRIB},
+		{"create and write", Create | Write, IN_CREATE | IN_MODIFY},
+		{"zero", 0, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := opToMask(c.op); got != c.want {
+				t.Errorf("opToMask(%v) = %#x, want %#x", c.op, got, c.want)
+			}
+		})
+	}
+}
+
+func TestMaskToOp(t *testing.T) {
+	cases := []struct {
+		name string
+		mask uint32
+		want Op
+	}{
+		{"create", IN_CREATE, Create},
+		{"write", IN_MODIFY, Write},
+		{"delete", IN_DELETE, Remove},
+		{"delete self", IN_DELETE_SELF, Remove},
+		{"moved from", IN_MOVED_FROM, Rename},
+		{"moved to", IN_MOVED_TO, Rename},
+		{"move self", IN_MOVE_SELF, Rename},
+		{"attrib", IN_ATTRIB, Chmod},
+		{"isdir only, no op bits", IN_ISDIR, 0},
+		{"create and delete", IN_CREATE | IN_DELETE, Create | Remove},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := maskToOp(c.mask); got != c.want {
+				t.Errorf("maskToOp(%#x) = %v, want %v", c.mask, got, c.want)
+			}
+		})
+	}
+}
+
+func TestOpToMaskMaskToOpRoundTrip(t *testing.T) {
+	for _, op := range []Op{Create, Write, Remove, Rename, Chmod, Create | Write | Chmod} {
+		if got := maskToOp(opToMask(op)); got != op {
+			t.Errorf("maskToOp(opToMask(%v)) = %v, want %v", op, got, op)
+		}
+	}
+}
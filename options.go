@@ -0,0 +1,178 @@
+package gonotify
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Options configures optional include/exclude filtering for
+// NewDirWatcherWithOptions. The zero value watches everything, exactly like
+// NewDirWatcher.
+type Options struct {
+	// Include, if non-empty, restricts the events delivered on C to paths
+	// matching at least one of these gitignore-style glob patterns.
+	// Directories not matching Include are still walked and watched, since
+	// they may contain files that do.
+	Include []string
+
+	// Exclude skips paths matching any of these gitignore-style glob
+	// patterns, such as "node_modules" or ".git", both when walking the tree
+	// for watches and when filtering events before delivery on C. This is
+	// what keeps large repos from blowing past fs.inotify.max_user_watches.
+	Exclude []string
+
+	// IgnoreFile, if set (e.g. ".gitignore" or ".dockerignore"), is read
+	// from root and its patterns are merged into Exclude.
+	IgnoreFile string
+}
+
+func (o Options) patternSets(root string) (include, exclude *patternSet, err error) {
+
+	excludePatterns := append([]string(nil), o.Exclude...)
+
+	if o.IgnoreFile != "" {
+		patterns, err := readIgnoreFile(filepath.Join(root, o.IgnoreFile))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, nil, err
+		}
+		excludePatterns = append(excludePatterns, patterns...)
+	}
+
+	return newPatternSet(o.Include), newPatternSet(excludePatterns), nil
+}
+
+// readIgnoreFile reads a .gitignore/.dockerignore-style file, one pattern per
+// line, skipping blank lines and comments.
+func readIgnoreFile(path string) ([]string, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+
+	return patterns, scanner.Err()
+}
+
+// patternSet matches slash-separated relative paths against a list of
+// gitignore-style patterns, applied in the order given so that a later "!"
+// pattern can re-include what an earlier one excluded, the same way a real
+// .gitignore does. A pattern containing a "/" (leading or internal) is
+// anchored to the watched root; otherwise it's matched at any depth. "**"
+// matches across any number of path segments, including zero. Patterns are
+// matched against names only, not file-vs-directory type, so a pattern like
+// "build/" is treated the same as "build".
+type patternSet struct {
+	rules []patternRule
+}
+
+type patternRule struct {
+	negate   bool
+	rooted   bool
+	segments []string
+}
+
+func newPatternSet(patterns []string) *patternSet {
+	s := &patternSet{}
+
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		rule := patternRule{}
+		if strings.HasPrefix(p, "!") {
+			rule.negate = true
+			p = p[1:]
+		}
+
+		p = strings.TrimSuffix(p, "/")
+		rule.rooted = strings.Contains(p, "/")
+		p = strings.TrimPrefix(p, "/")
+
+		rule.segments = strings.Split(p, "/")
+		s.rules = append(s.rules, rule)
+	}
+
+	return s
+}
+
+func (s *patternSet) empty() bool {
+	return s == nil || len(s.rules) == 0
+}
+
+func (s *patternSet) match(relPath string) bool {
+	if s.empty() {
+		return false
+	}
+
+	segments := strings.Split(filepath.ToSlash(relPath), "/")
+
+	matched := false
+	for _, rule := range s.rules {
+		if rule.matches(segments) {
+			matched = !rule.negate
+		}
+	}
+
+	return matched
+}
+
+// matches reports whether rule matches path. Once the pattern is fully
+// consumed it matches regardless of any path segments left over: a pattern
+// that matches a directory also matches everything below it.
+func (r patternRule) matches(path []string) bool {
+	if r.rooted {
+		return matchSegments(r.segments, path)
+	}
+
+	for start := 0; start <= len(path); start++ {
+		if matchSegments(r.segments, path[start:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+
+	if pattern[0] == "**" {
+		if len(pattern) == 1 {
+			return true
+		}
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(path) == 0 {
+		return false
+	}
+
+	if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+		return false
+	}
+
+	return matchSegments(pattern[1:], path[1:])
+}
@@ -0,0 +1,61 @@
+package gonotify
+
+import "testing"
+
+func TestPatternSetMatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		patterns []string
+		path     string
+		want     bool
+	}{
+		{"plain name matches at root", []string{"node_modules"}, "node_modules", true},
+		{"plain name matches nested", []string{"node_modules"}, "src/node_modules", true},
+		{"plain name matches contents of matched dir", []string{"node_modules"}, "src/node_modules/left-pad/index.js", true},
+		{"plain name does not match unrelated file", []string{"node_modules"}, "src/main.go", false},
+
+		{"leading slash anchors to root", []string{"/build"}, "build", true},
+		{"leading slash does not match nested", []string{"/build"}, "src/build", false},
+
+		{"internal slash anchors to root", []string{"build/output"}, "build/output", true},
+		{"internal slash does not match nested", []string{"build/output"}, "src/build/output", false},
+
+		{"double star matches any depth", []string{"**/node_modules"}, "a/b/c/node_modules", true},
+		{"double star matches zero depth", []string{"**/node_modules"}, "node_modules", true},
+
+		{"double star spans middle segments", []string{"a/**/z"}, "a/z", true},
+		{"double star spans multiple middle segments", []string{"a/**/z"}, "a/b/c/z", true},
+		{"double star suffix matches everything below", []string{"build/**"}, "build/out/bin", true},
+
+		{"glob star within a segment", []string{"*.log"}, "debug.log", true},
+		{"unanchored glob star matches a nested file name", []string{"*.log"}, "logs/debug.log", true},
+
+		{"negation re-includes a previously excluded path", []string{"*.log", "!keep.log"}, "keep.log", false},
+		{"negation does not affect other matches", []string{"*.log", "!keep.log"}, "debug.log", true},
+		{"later non-negated pattern re-excludes", []string{"*.log", "!keep.log", "keep.log"}, "keep.log", true},
+
+		{"empty pattern set matches nothing", nil, "anything", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := newPatternSet(c.patterns)
+			if got := s.match(c.path); got != c.want {
+				t.Errorf("match(%q) with patterns %v = %v, want %v", c.path, c.patterns, got, c.want)
+			}
+		})
+	}
+}
+
+func TestPatternSetEmpty(t *testing.T) {
+	if !newPatternSet(nil).empty() {
+		t.Error("newPatternSet(nil) should be empty")
+	}
+	if newPatternSet([]string{"foo"}).empty() {
+		t.Error("newPatternSet([]string{\"foo\"}) should not be empty")
+	}
+	var nilSet *patternSet
+	if !nilSet.empty() {
+		t.Error("a nil *patternSet should be empty")
+	}
+}
@@ -0,0 +1,214 @@
+package gonotify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pollingWatcher implements Watcher by periodically walking the watched
+// paths and diffing file mtime/size/mode against what was seen on the
+// previous pass. It is slower and coarser than a native backend, but it
+// works on filesystems that don't deliver native events, such as NFS/SMB
+// mounts and some FUSE filesystems.
+type pollingWatcher struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	interval time.Duration
+	root     string
+
+	paths map[string]uint32 // path -> mask
+
+	snapshot map[string]pollingStat
+
+	events chan FileEvent
+	errors chan error
+}
+
+type pollingStat struct {
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+// NewPollingDirWatcher creates a DirWatcher-compatible watcher that discovers
+// changes under root by polling every interval instead of relying on native
+// filesystem events. Use it for network/FUSE filesystems where inotify
+// doesn't deliver events, or fall back to it automatically with
+// NewAutoDirWatcher.
+func NewPollingDirWatcher(ctx context.Context, fileMask uint32, root string, interval time.Duration) (*DirWatcher, error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	w := &pollingWatcher{
+		ctx:      ctx,
+		cancel:   cancel,
+		interval: interval,
+		root:     root,
+		paths:    map[string]uint32{root: IN_ALL_EVENTS},
+		snapshot: make(map[string]pollingStat),
+		events:   make(chan FileEvent),
+		errors:   make(chan error),
+	}
+
+	dw := &DirWatcher{
+		C:       make(chan FileEvent),
+		watcher: w,
+		mu:      &sync.Mutex{},
+		trees:   map[string]map[string]bool{root: {root: true}},
+		roots:   map[string]string{root: root},
+		root:    root,
+	}
+
+	go w.loop(root)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-w.events:
+				if !ok {
+					dw.C <- FileEvent{Eof: true}
+					cancel()
+					return
+				}
+
+				if event.Mask&fileMask == 0 {
+					continue
+				}
+
+				dw.C <- event
+			}
+		}
+	}()
+
+	return dw, nil
+}
+
+func (w *pollingWatcher) loop(root string) {
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	// Starting from an empty snapshot, this first poll sees every pre-existing
+	// file as "new" and emits IN_CREATE for it, matching the synthetic
+	// IN_CREATE events NewDirWatcher emits at startup so the two backends are
+	// interchangeable.
+	w.poll(root)
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			close(w.events)
+			return
+		case <-ticker.C:
+			w.poll(root)
+		}
+	}
+}
+
+// poll walks root, diffs against the previous snapshot and emits synthetic
+// FileEvents for anything that changed.
+func (w *pollingWatcher) poll(root string) {
+
+	seen := make(map[string]bool, len(w.snapshot))
+
+	filepath.Walk(root, func(path string, f os.FileInfo, err error) error {
+		if err != nil || f.IsDir() {
+			return nil
+		}
+
+		seen[path] = true
+
+		stat := pollingStat{
+			size:    f.Size(),
+			mode:    f.Mode(),
+			modTime: f.ModTime(),
+		}
+
+		prev, ok := w.snapshot[path]
+		w.snapshot[path] = stat
+
+		if !ok {
+			w.emit(path, IN_CREATE)
+			return nil
+		}
+
+		if prev.mode != stat.mode {
+			w.emit(path, IN_ATTRIB)
+		}
+
+		if prev.size != stat.size || !prev.modTime.Equal(stat.modTime) {
+			w.emit(path, IN_MODIFY)
+		}
+
+		return nil
+	})
+
+	for path := range w.snapshot {
+		if !seen[path] {
+			delete(w.snapshot, path)
+			w.emit(path, IN_DELETE)
+		}
+	}
+}
+
+func (w *pollingWatcher) emit(path string, mask uint32) {
+	select {
+	case w.events <- FileEvent{InotifyEvent: InotifyEvent{Name: path, Mask: mask}}:
+	case <-w.ctx.Done():
+	}
+}
+
+func (w *pollingWatcher) Events() <-chan FileEvent {
+	return w.events
+}
+
+func (w *pollingWatcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Add records path so DirWatcher.AddTree can track it, satisfying the
+// Watcher interface. It does not expand what poll scans: that always walks
+// the single root NewPollingDirWatcher was given, since a recursive walk
+// already covers every path below it on its own. A path outside that root
+// would silently never be scanned, so it's rejected instead.
+func (w *pollingWatcher) Add(path string, mask uint32) error {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return fmt.Errorf("gonotify: polling watcher for %q can't watch %q outside its root", w.root, path)
+	}
+
+	w.paths[path] = mask
+	return nil
+}
+
+func (w *pollingWatcher) Remove(path string) error {
+	delete(w.paths, path)
+	return nil
+}
+
+func (w *pollingWatcher) Close() error {
+	w.cancel()
+	return nil
+}
+
+// NewAutoDirWatcher creates a DirWatcher using the platform's native backend
+// (inotify, kqueue, or ReadDirectoryChangesW), falling back to
+// NewPollingDirWatcher if the native backend fails to initialize, e.g.
+// because of an exhausted inotify watch limit, or because the platform (like
+// illumos) has no native backend at all.
+func NewAutoDirWatcher(ctx context.Context, fileMask uint32, root string, pollInterval time.Duration) (*DirWatcher, error) {
+
+	dw, err := NewDirWatcher(ctx, fileMask, root)
+	if err == nil {
+		return dw, nil
+	}
+
+	return NewPollingDirWatcher(ctx, fileMask, root, pollInterval)
+}
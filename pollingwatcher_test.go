@@ -0,0 +1,125 @@
+package gonotify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestPollingWatcher(t *testing.T, root string) *pollingWatcher {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	return &pollingWatcher{
+		ctx:      ctx,
+		cancel:   cancel,
+		interval: time.Hour, // the test drives poll() directly, not the ticker
+		paths:    map[string]uint32{root: IN_ALL_EVENTS},
+		snapshot: make(map[string]pollingStat),
+		events:   make(chan FileEvent, 16),
+		errors:   make(chan error, 1),
+	}
+}
+
+func pollEvents(w *pollingWatcher, root string) []FileEvent {
+	w.poll(root)
+
+	var events []FileEvent
+	for {
+		select {
+		case e := <-w.events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func TestPollingWatcherEmitsCreateForNewFile(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := newTestPollingWatcher(t, root)
+
+	events := pollEvents(w, root)
+	if len(events) != 1 || events[0].Name != path || events[0].Mask != IN_CREATE {
+		t.Fatalf("got %+v, want a single IN_CREATE for %q", events, path)
+	}
+
+	// A second poll with nothing changed should be silent.
+	if events := pollEvents(w, root); len(events) != 0 {
+		t.Errorf("unexpected events on unchanged poll: %+v", events)
+	}
+}
+
+func TestPollingWatcherEmitsModifyOnSizeChange(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := newTestPollingWatcher(t, root)
+	pollEvents(w, root) // consume the initial IN_CREATE
+
+	// Force a detectable mtime change regardless of filesystem timestamp
+	// resolution.
+	future := time.Now().Add(time.Minute)
+	if err := os.WriteFile(path, []byte("hello, world"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	events := pollEvents(w, root)
+	if len(events) != 1 || events[0].Name != path || events[0].Mask != IN_MODIFY {
+		t.Fatalf("got %+v, want a single IN_MODIFY for %q", events, path)
+	}
+}
+
+func TestPollingWatcherEmitsAttribOnModeChange(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := newTestPollingWatcher(t, root)
+	pollEvents(w, root) // consume the initial IN_CREATE
+
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	events := pollEvents(w, root)
+	if len(events) != 1 || events[0].Name != path || events[0].Mask != IN_ATTRIB {
+		t.Fatalf("got %+v, want a single IN_ATTRIB for %q", events, path)
+	}
+}
+
+func TestPollingWatcherEmitsDeleteForRemovedFile(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := newTestPollingWatcher(t, root)
+	pollEvents(w, root) // consume the initial IN_CREATE
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	events := pollEvents(w, root)
+	if len(events) != 1 || events[0].Name != path || events[0].Mask != IN_DELETE {
+		t.Fatalf("got %+v, want a single IN_DELETE for %q", events, path)
+	}
+}
@@ -0,0 +1,40 @@
+package gonotify
+
+import "context"
+
+// Watcher is the portable interface implemented by every backend: the native
+// inotify backend on Linux, kqueue on *BSD/macOS, and ReadDirectoryChangesW on
+// Windows. DirWatcher is built on top of a Watcher so that it works the same
+// way regardless of which backend the current platform picked.
+//
+// illumos has no pure-Go binding for its File Events Notification facility
+// (port_create(3C)/port_get(3C) require cgo), so it has no native backend
+// here; NewAutoDirWatcher's polling fallback covers it instead.
+type Watcher interface {
+	// Events returns the channel carrying normalized FileEvents. Mask is a
+	// bitwise OR of IN_* constants, translated from the backend's native
+	// representation.
+	Events() <-chan FileEvent
+
+	// Errors returns the channel carrying errors encountered while watching.
+	Errors() <-chan error
+
+	// Add starts watching path for the events in mask (bitwise OR of IN_*
+	// constants).
+	Add(path string, mask uint32) error
+
+	// Remove stops watching path.
+	Remove(path string) error
+
+	// Close releases all resources held by the watcher. It returns without
+	// waiting for the backend to stop and does not itself close Events or
+	// Errors.
+	Close() error
+}
+
+// newWatcher creates the Watcher backend appropriate for the current
+// platform. Each platform's backend file (backend_*.go, selected by file
+// name suffix or build constraint) provides its own newPlatformWatcher.
+func newWatcher(ctx context.Context) (Watcher, error) {
+	return newPlatformWatcher(ctx)
+}